@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// version and commit are set at build time via:
+//
+//	go build -ldflags "-X main.version=... -X main.commit=..."
+var (
+	version = "dev"
+	commit  = "none"
+)
+
+var buildInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: namespace + "_exporter_build_info",
+	Help: "Build information about the exporter binary. Constant 1, labels carry the version/commit/goversion",
+}, []string{"version", "commit", "goversion"})
+
+func init() {
+	buildInfo.WithLabelValues(version, commit, runtime.Version()).Set(1)
+}
+
+// newDebugMux builds the handler for the admin/debug listener: pprof
+// profiles, a liveness probe, and a config reload endpoint. It is served on
+// a separate port from -listen so profile downloads never contend with
+// Prometheus scraping /metrics.
+func newDebugMux() *http.ServeMux {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/pprof/heap", pprof.Handler("heap"))
+	mux.Handle("/debug/pprof/goroutine", pprof.Handler("goroutine"))
+	mux.Handle("/debug/pprof/block", pprof.Handler("block"))
+	mux.Handle("/debug/pprof/mutex", pprof.Handler("mutex"))
+
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/-/reload", handleReload)
+
+	return mux
+}
+
+// handleReload serves /-/reload. The exporter is configured entirely by
+// flags and per-probe query parameters and has no config file, so a POST
+// only logs that there is nothing to reload and answers 200; any other
+// method gets 405.
+func handleReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	log.Print("/-/reload: no config file configured, nothing to reload")
+	w.WriteHeader(http.StatusOK)
+}
+
+// serveDebug starts the admin/debug listener on addr. It runs for the
+// lifetime of the process; main logs and exits if it fails to bind.
+func serveDebug(addr string) error {
+	log.Printf("debug listener (pprof, healthz, reload) on %s", addr)
+	return http.ListenAndServe(addr, newDebugMux())
+}
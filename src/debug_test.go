@@ -0,0 +1,40 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDebugMuxHealthz(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rec := httptest.NewRecorder()
+
+	newDebugMux().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("/healthz status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestDebugMuxReloadRejectsGet(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/-/reload", nil)
+	rec := httptest.NewRecorder()
+
+	newDebugMux().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET /-/reload status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestDebugMuxReloadAcceptsPost(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/-/reload", nil)
+	rec := httptest.NewRecorder()
+
+	newDebugMux().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("POST /-/reload status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const i2pcontrolAPIVersion = 1
+
+// tokenExpiredCode is the JSON-RPC error code I2PControl returns once an
+// Authenticate token has expired or was never valid.
+const tokenExpiredCode = -32003
+
+// routerInfoFields are the RouterInfo keys requested on every scrape. The
+// I2PControl wire format wants each key present in the params object with a
+// null value; the response echoes them back populated.
+var routerInfoFields = []string{
+	"i2p.router.uptime",
+	"i2p.router.net.status",
+	"i2p.router.net.bw.inbound.1s",
+	"i2p.router.net.bw.outbound.1s",
+	"i2p.router.net.total.received.bytes",
+	"i2p.router.net.total.sent.bytes",
+	"i2p.router.netdb.knownpeers",
+	"i2p.router.version",
+}
+
+// i2pcontrolClient is an alternative scrape backend that talks I2PControl,
+// i2pd's JSON-RPC 2.0 management API, instead of scraping HTML. It survives
+// across probes so the Authenticate token it negotiates can be reused
+// instead of re-authenticating on every scrape.
+type i2pcontrolClient struct {
+	httpClient *http.Client
+	password   string
+
+	mu     sync.Mutex
+	tokens map[string]string // target -> cached Authenticate token
+}
+
+func newI2PControlClient(password string, tlsSkipVerify bool) *i2pcontrolClient {
+	transport := &http.Transport{}
+	if tlsSkipVerify {
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	return &i2pcontrolClient{
+		httpClient: &http.Client{Transport: transport},
+		password:   password,
+		tokens:     make(map[string]string),
+	}
+}
+
+func (c *i2pcontrolClient) Scrape(ctx context.Context, target string) (i2pdStats, error) {
+	token, err := c.tokenFor(ctx, target)
+	if err != nil {
+		return i2pdStats{}, fmt.Errorf("authenticate: %w", err)
+	}
+
+	fields, err := c.routerInfo(ctx, target, token)
+	if rpcErr, ok := err.(*rpcError); ok && rpcErr.Code == tokenExpiredCode {
+		c.forgetToken(target)
+		if token, err = c.tokenFor(ctx, target); err != nil {
+			return i2pdStats{}, fmt.Errorf("re-authenticate: %w", err)
+		}
+		fields, err = c.routerInfo(ctx, target, token)
+	}
+	if err != nil {
+		return i2pdStats{}, fmt.Errorf("RouterInfo: %w", err)
+	}
+
+	return parseRouterInfo(fields), nil
+}
+
+func (c *i2pcontrolClient) tokenFor(ctx context.Context, target string) (string, error) {
+	c.mu.Lock()
+	token, cached := c.tokens[target]
+	c.mu.Unlock()
+	if cached {
+		return token, nil
+	}
+
+	var result struct {
+		Token string `json:"Token"`
+	}
+	params := map[string]any{"API": i2pcontrolAPIVersion, "Password": c.password}
+	if err := c.call(ctx, target, "Authenticate", params, &result); err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	c.tokens[target] = result.Token
+	c.mu.Unlock()
+	return result.Token, nil
+}
+
+func (c *i2pcontrolClient) forgetToken(target string) {
+	c.mu.Lock()
+	delete(c.tokens, target)
+	c.mu.Unlock()
+}
+
+func (c *i2pcontrolClient) routerInfo(ctx context.Context, target, token string) (map[string]any, error) {
+	params := map[string]any{"token": token}
+	for _, field := range routerInfoFields {
+		params[field] = nil
+	}
+
+	var result map[string]any
+	if err := c.call(ctx, target, "RouterInfo", params, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type rpcRequest struct {
+	ID      int    `json:"id"`
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("I2PControl error %d: %s", e.Code, e.Message)
+}
+
+func (c *i2pcontrolClient) call(ctx context.Context, target, method string, params, result any) error {
+	body, err := json.Marshal(rpcRequest{ID: 1, JSONRPC: "2.0", Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var rpcResp rpcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	if rpcResp.Error != nil {
+		return rpcResp.Error
+	}
+	if result != nil {
+		if err := json.Unmarshal(rpcResp.Result, result); err != nil {
+			return fmt.Errorf("decode result: %w", err)
+		}
+	}
+	return nil
+}
+
+// parseRouterInfo maps the RouterInfo fields I2PControl returned onto the
+// shared i2pdStats type, leaving anything not covered by routerInfoFields
+// unset.
+func parseRouterInfo(fields map[string]any) i2pdStats {
+	var stats i2pdStats
+
+	if v, ok := numberField(fields, "i2p.router.uptime"); ok {
+		uptime := time.Duration(v) * time.Millisecond
+		stats.Uptime = &uptime
+	}
+	if v, ok := stringField(fields, "i2p.router.net.status"); ok {
+		ok4 := v == "OK"
+		stats.NetStatusV4 = &ok4
+	}
+	if v, ok := numberField(fields, "i2p.router.netdb.knownpeers"); ok {
+		stats.Routers = &v
+	}
+	if v, ok := stringField(fields, "i2p.router.version"); ok {
+		stats.Version = v
+	}
+
+	traffic := map[string]*trafficSample{}
+	sampleFor := func(direction string) *trafficSample {
+		s, ok := traffic[direction]
+		if !ok {
+			s = &trafficSample{Direction: direction}
+			traffic[direction] = s
+		}
+		return s
+	}
+	if v, ok := numberField(fields, "i2p.router.net.bw.inbound.1s"); ok {
+		sampleFor("received").RateBytesPerSecond = v
+	}
+	if v, ok := numberField(fields, "i2p.router.net.bw.outbound.1s"); ok {
+		sampleFor("sent").RateBytesPerSecond = v
+	}
+	if v, ok := numberField(fields, "i2p.router.net.total.received.bytes"); ok {
+		sampleFor("received").TotalBytes = v
+	}
+	if v, ok := numberField(fields, "i2p.router.net.total.sent.bytes"); ok {
+		sampleFor("sent").TotalBytes = v
+	}
+	for _, s := range traffic {
+		stats.Traffic = append(stats.Traffic, *s)
+	}
+
+	return stats
+}
+
+func numberField(fields map[string]any, key string) (float64, bool) {
+	v, ok := fields[key]
+	if !ok {
+		return 0, false
+	}
+	f, ok := v.(float64)
+	return f, ok
+}
+
+func stringField(fields map[string]any, key string) (string, bool) {
+	v, ok := fields[key]
+	if !ok {
+		return "", false
+	}
+	s, ok := v.(string)
+	return s, ok
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// i2pcontrolStub emulates just enough of i2pd's I2PControl JSON-RPC API to
+// exercise the authenticate/retry logic: a fixed password, a token that can
+// be force-expired, and a canned RouterInfo response.
+type i2pcontrolStub struct {
+	password        string
+	validToken      string
+	expireOnce      bool
+	authCalls       int
+	routerInfoCalls int
+}
+
+func (s *i2pcontrolStub) handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req rpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		switch req.Method {
+		case "Authenticate":
+			s.authCalls++
+			params, _ := req.Params.(map[string]any)
+			if params["Password"] != s.password {
+				writeRPCError(w, req.ID, -32001, "Invalid password")
+				return
+			}
+			writeRPCResult(w, req.ID, map[string]any{"Token": s.validToken})
+
+		case "RouterInfo":
+			s.routerInfoCalls++
+			params, _ := req.Params.(map[string]any)
+			token, _ := params["token"].(string)
+			if token != s.validToken || (s.expireOnce && s.routerInfoCalls == 1) {
+				writeRPCError(w, req.ID, tokenExpiredCode, "Token expired")
+				return
+			}
+			writeRPCResult(w, req.ID, map[string]any{
+				"i2p.router.uptime":                   float64(90061000),
+				"i2p.router.net.status":               "OK",
+				"i2p.router.net.bw.inbound.1s":        float64(1024),
+				"i2p.router.net.bw.outbound.1s":       float64(2048),
+				"i2p.router.net.total.received.bytes": float64(1073741824),
+				"i2p.router.net.total.sent.bytes":     float64(2147483648),
+				"i2p.router.netdb.knownpeers":         float64(10100),
+				"i2p.router.version":                  "2.59.0",
+			})
+
+		default:
+			writeRPCError(w, req.ID, -32601, "Method not found")
+		}
+	}
+}
+
+func writeRPCResult(w http.ResponseWriter, id int, result any) {
+	body, _ := json.Marshal(result)
+	json.NewEncoder(w).Encode(rpcResponse{Result: body})
+}
+
+func writeRPCError(w http.ResponseWriter, id int, code int, message string) {
+	json.NewEncoder(w).Encode(rpcResponse{Error: &rpcError{Code: code, Message: message}})
+}
+
+func TestI2PControlScrape(t *testing.T) {
+	stub := &i2pcontrolStub{password: "itoopie", validToken: "tok-1"}
+	srv := httptest.NewServer(stub.handler())
+	defer srv.Close()
+
+	client := newI2PControlClient(stub.password, false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	stats, err := client.Scrape(ctx, srv.URL)
+	if err != nil {
+		t.Fatalf("Scrape: %v", err)
+	}
+
+	if stats.Uptime == nil || *stats.Uptime != 90061*time.Second {
+		t.Errorf("Uptime = %v, want 90061s", stats.Uptime)
+	}
+	if stats.NetStatusV4 == nil || !*stats.NetStatusV4 {
+		t.Errorf("NetStatusV4 = %v, want true", stats.NetStatusV4)
+	}
+	if stats.Routers == nil || *stats.Routers != 10100 {
+		t.Errorf("Routers = %v, want 10100", stats.Routers)
+	}
+	if stats.Version != "2.59.0" {
+		t.Errorf("Version = %q, want 2.59.0", stats.Version)
+	}
+	if stub.authCalls != 1 {
+		t.Errorf("authCalls = %d, want 1 (token should be cached)", stub.authCalls)
+	}
+
+	if _, err := client.Scrape(ctx, srv.URL); err != nil {
+		t.Fatalf("second Scrape: %v", err)
+	}
+	if stub.authCalls != 1 {
+		t.Errorf("authCalls after second scrape = %d, want 1 (no re-authenticate)", stub.authCalls)
+	}
+}
+
+func TestI2PControlRetriesExpiredToken(t *testing.T) {
+	stub := &i2pcontrolStub{password: "itoopie", validToken: "tok-1", expireOnce: true}
+	srv := httptest.NewServer(stub.handler())
+	defer srv.Close()
+
+	client := newI2PControlClient(stub.password, false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := client.Scrape(ctx, srv.URL); err != nil {
+		t.Fatalf("Scrape: %v", err)
+	}
+	if stub.authCalls != 2 {
+		t.Errorf("authCalls = %d, want 2 (one retry after token expiry)", stub.authCalls)
+	}
+}
+
+func TestI2PControlWrongPassword(t *testing.T) {
+	stub := &i2pcontrolStub{password: "itoopie", validToken: "tok-1"}
+	srv := httptest.NewServer(stub.handler())
+	defer srv.Close()
+
+	client := newI2PControlClient("wrong", false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := client.Scrape(ctx, srv.URL); err == nil {
+		t.Fatal("expected an error for a wrong password")
+	}
+}
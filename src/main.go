@@ -1,247 +1,361 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
-	"io"
 	"log"
-	"math"
 	"net/http"
-	"regexp"
-	"strconv"
 	"strings"
 	"time"
-)
 
-var (
-	reUptime      = regexp.MustCompile(`(?i)<b>Uptime:</b>\s*(.+?)<br`)
-	reUptimePart  = regexp.MustCompile(`(\d+)\s+(day|hour|minute|second)`)
-	reNetStatus   = regexp.MustCompile(`(?i)<b>Network status:</b>\s*(\w+)`)
-	reNetStatusV6 = regexp.MustCompile(`(?i)<b>Network status v6:</b>\s*(\w+)`)
-	reTunnelRate  = regexp.MustCompile(`(?i)<b>Tunnel creation success rate:</b>\s*([\d.]+)\s*%`)
-	reTraffic     = regexp.MustCompile(`(?i)<b>(Received|Sent|Transit):</b>\s*([\d.]+)\s*(\w+)\s*\(([\d.]+)\s*(\w+/s)\)`)
-	reRouters     = regexp.MustCompile(`(?i)<b>Routers:</b>\s*(\d+)`)
-	reFloodfills  = regexp.MustCompile(`(?i)<b>Floodfills:</b>\s*(\d+)`)
-	reLeaseSets   = regexp.MustCompile(`(?i)<b>LeaseSets:</b>\s*(\d+)`)
-	reClientTun   = regexp.MustCompile(`(?i)<b>Client Tunnels:</b>\s*(\d+)`)
-	reTransitTun  = regexp.MustCompile(`(?i)<b>Transit Tunnels:</b>\s*(\d+)`)
-	reVersion     = regexp.MustCompile(`(?i)<b>Version:</b>\s*([\d.]+)`)
-	reCaps        = regexp.MustCompile(`(?i)<b>Router Caps:</b>\s*(\w+)`)
-	reService     = regexp.MustCompile(`<tr><td>([^<]+)</td><td\s+class='(enabled|disabled)'`)
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// Example prometheus.yml for scraping several i2pd routers through a single
+// exporter instance, blackbox_exporter-style:
+//
+//	scrape_configs:
+//	  - job_name: i2pd
+//	    metrics_path: /probe
+//	    static_configs:
+//	      - targets:
+//	          - http://10.0.0.2:7070
+//	          - http://10.0.0.3:7070
+//	    relabel_configs:
+//	      - source_labels: [__address__]
+//	        target_label: __param_target
+//	      - source_labels: [__param_target]
+//	        target_label: instance
+//	      - target_label: __address__
+//	        replacement: exporter:9101
+
+const namespace = "i2pd"
+
+// scraper fetches i2pd router metrics for a single target. webconsoleScraper
+// and i2pcontrolClient are the two implementations; main() picks one at
+// startup based on -backend.
+type scraper interface {
+	Scrape(ctx context.Context, target string) (i2pdStats, error)
+}
+
 func main() {
-	listenAddr := flag.String("listen", ":9101", "address to listen on for metrics")
-	i2pdURL := flag.String("url", "http://127.0.0.1:7070", "i2pd web console URL")
-	timeout := flag.Duration("timeout", 5*time.Second, "HTTP client timeout")
+	listenAddr := flag.String("listen", ":9101", "address to listen on for metrics and probes")
+	i2pdURL := flag.String("url", "http://127.0.0.1:7070", "default i2pd target used when /probe is called without a target")
+	timeout := flag.Duration("timeout", 5*time.Second, "default scrape timeout, overridable per-probe with ?timeout=")
+	backend := flag.String("backend", "webconsole", "scrape backend: webconsole (HTML scraping) or i2pcontrol (JSON-RPC)")
+	password := flag.String("password", "", "I2PControl password (only used with -backend=i2pcontrol)")
+	tlsSkipVerify := flag.Bool("tls-skip-verify", false, "skip TLS certificate verification for I2PControl (only used with -backend=i2pcontrol)")
+	scrapeRetries := flag.Int("scrape-retries", 3, "maximum scrape attempts per probe before giving up")
+	pages := flag.String("pages", "", "comma-separated auxiliary webconsole pages to scrape in addition to the home page (transports, transit_tunnels, sam_sessions, i2p_tunnels; only used with -backend=webconsole)")
+	debugListen := flag.String("debug-listen", "", "address for a separate admin/debug listener (pprof, /healthz, /-/reload); disabled if empty")
 	flag.Parse()
 
-	client := &http.Client{Timeout: *timeout}
+	webconsolePages, err := parsePages(*pages)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	s, err := newScraper(*backend, *password, *tlsSkipVerify, webconsolePages)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s = newRetryingScraper(s, *scrapeRetries)
 
-	http.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
-		serveMetrics(w, client, *i2pdURL)
+	prometheus.MustRegister(scrapeAttemptsTotal, scrapeRetriesTotal, buildInfo)
+
+	if *debugListen != "" {
+		go func() {
+			log.Fatal(serveDebug(*debugListen))
+		}()
+	}
+
+	// A private mux, never http.DefaultServeMux: importing net/http/pprof for
+	// the debug listener registers pprof's handlers on DefaultServeMux as a
+	// side effect of its own init(), and the main listener must not serve
+	// them.
+	mainMux := http.NewServeMux()
+	mainMux.Handle("/metrics", promhttp.Handler())
+	mainMux.HandleFunc("/probe", func(w http.ResponseWriter, r *http.Request) {
+		probeHandler(w, r, s, *i2pdURL, *timeout)
 	})
-	http.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+	mainMux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
 		w.Header().Set("Content-Type", "text/html")
-		fmt.Fprint(w, `<html><body><h1>i2pd Exporter</h1><p><a href="/metrics">Metrics</a></p></body></html>`)
+		fmt.Fprint(w, `<html><body><h1>i2pd Exporter</h1><p><a href="/metrics">Metrics</a> &middot; <a href="/probe">Probe</a></p></body></html>`)
 	})
 
-	log.Printf("i2pd exporter listening on %s, scraping %s", *listenAddr, *i2pdURL)
-	log.Fatal(http.ListenAndServe(*listenAddr, nil))
+	log.Printf("i2pd exporter listening on %s, backend %s, default target %s", *listenAddr, *backend, *i2pdURL)
+	log.Fatal((&http.Server{Addr: *listenAddr, Handler: mainMux}).ListenAndServe())
 }
 
-func serveMetrics(w http.ResponseWriter, client *http.Client, url string) {
-	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
-
-	start := time.Now()
-
-	resp, err := client.Get(url)
-	if err != nil {
-		log.Printf("scrape error: %v", err)
-		writeDown(w, time.Since(start))
-		return
+func newScraper(backend, password string, tlsSkipVerify bool, pages []webconsolePage) (scraper, error) {
+	switch backend {
+	case "webconsole":
+		return newWebconsoleScraper(pages), nil
+	case "i2pcontrol":
+		return newI2PControlClient(password, tlsSkipVerify), nil
+	default:
+		return nil, fmt.Errorf("unknown -backend %q (want webconsole or i2pcontrol)", backend)
 	}
-	defer resp.Body.Close()
+}
 
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("i2pd returned status %d", resp.StatusCode)
-		writeDown(w, time.Since(start))
-		return
+// parsePages parses the comma-separated -pages flag into webconsolePages,
+// always including pageHome. An empty string means "home page only".
+func parsePages(flagValue string) ([]webconsolePage, error) {
+	pages := []webconsolePage{pageHome}
+	if flagValue == "" {
+		return pages, nil
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Printf("read error: %v", err)
-		writeDown(w, time.Since(start))
-		return
+	for _, p := range strings.Split(flagValue, ",") {
+		page := webconsolePage(strings.TrimSpace(p))
+		if page == "" || page == pageHome {
+			continue
+		}
+		if !validWebconsolePage(page) {
+			return nil, fmt.Errorf("unknown -pages entry %q", page)
+		}
+		pages = append(pages, page)
 	}
-
-	duration := time.Since(start)
-	fmt.Fprint(w, collectMetrics(string(body), duration))
-}
-
-func writeDown(w http.ResponseWriter, duration time.Duration) {
-	fmt.Fprint(w, "# HELP i2pd_up Whether the i2pd console is reachable\n")
-	fmt.Fprint(w, "# TYPE i2pd_up gauge\n")
-	fmt.Fprint(w, "i2pd_up 0\n")
-	fmt.Fprintf(w, "# HELP i2pd_scrape_duration_seconds Time spent scraping i2pd console\n")
-	fmt.Fprintf(w, "# TYPE i2pd_scrape_duration_seconds gauge\n")
-	fmt.Fprintf(w, "i2pd_scrape_duration_seconds %s\n", fmtFloat(duration.Seconds()))
+	return pages, nil
 }
 
-func collectMetrics(html string, scrapeDuration time.Duration) string {
-	w := &promWriter{seen: make(map[string]bool)}
-
-	w.gauge("i2pd_up", "Whether the i2pd console is reachable", 1)
-	w.gauge("i2pd_scrape_duration_seconds", "Time spent scraping i2pd console", scrapeDuration.Seconds())
-
-	// Uptime
-	if m := reUptime.FindStringSubmatch(html); m != nil {
-		var secs float64
-		for _, p := range reUptimePart.FindAllStringSubmatch(m[1], -1) {
-			n, _ := strconv.ParseFloat(p[1], 64)
-			switch {
-			case strings.HasPrefix(p[2], "day"):
-				secs += n * 86400
-			case strings.HasPrefix(p[2], "hour"):
-				secs += n * 3600
-			case strings.HasPrefix(p[2], "minute"):
-				secs += n * 60
-			case strings.HasPrefix(p[2], "second"):
-				secs += n
-			}
-		}
-		w.gauge("i2pd_uptime_seconds", "Router uptime in seconds", secs)
+// probeHandler scrapes the target named by the "target" query parameter (or
+// defaultTarget if it is absent) and serves the result as a one-off
+// Prometheus registry, the same way blackbox_exporter's /probe endpoint
+// works.
+func probeHandler(w http.ResponseWriter, r *http.Request, s scraper, defaultTarget string, defaultTimeout time.Duration) {
+	target := r.URL.Query().Get("target")
+	if target == "" {
+		target = defaultTarget
 	}
 
-	// Network status
-	if m := reNetStatus.FindStringSubmatch(html); m != nil {
-		w.gaugeL("i2pd_network_status", "Network status (1=OK, 0=other)",
-			bval(m[1] == "OK"), "protocol", "v4")
-	}
-	if m := reNetStatusV6.FindStringSubmatch(html); m != nil {
-		w.gaugeL("i2pd_network_status", "",
-			bval(m[1] == "OK"), "protocol", "v6")
+	timeout := defaultTimeout
+	if ts := r.URL.Query().Get("timeout"); ts != "" {
+		d, err := time.ParseDuration(ts)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid timeout %q: %v", ts, err), http.StatusBadRequest)
+			return
+		}
+		timeout = d
 	}
 
-	// Tunnel creation success rate
-	if m := reTunnelRate.FindStringSubmatch(html); m != nil {
-		v, _ := strconv.ParseFloat(m[1], 64)
-		w.gauge("i2pd_tunnel_creation_success_rate_percent", "Tunnel creation success rate", v)
-	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
 
-	// Traffic (received, sent, transit)
-	for _, m := range reTraffic.FindAllStringSubmatch(html, -1) {
-		dir := strings.ToLower(m[1])
-		total, _ := strconv.ParseFloat(m[2], 64)
-		total *= unitBytes(m[3])
-		rate, _ := strconv.ParseFloat(m[4], 64)
-		rate *= unitBytes(strings.TrimSuffix(m[5], "/s"))
-
-		w.counterL("i2pd_traffic_bytes_total", "Total traffic in bytes",
-			total, "direction", dir)
-		w.gaugeL("i2pd_traffic_bytes_per_second", "Traffic rate in bytes per second",
-			rate, "direction", dir)
-	}
+	reg := probe(ctx, s, target)
+	promhttp.HandlerFor(reg, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+}
 
-	// Router database
-	if m := reRouters.FindStringSubmatch(html); m != nil {
-		v, _ := strconv.ParseFloat(m[1], 64)
-		w.gauge("i2pd_routers", "Number of known routers", v)
-	}
-	if m := reFloodfills.FindStringSubmatch(html); m != nil {
-		v, _ := strconv.ParseFloat(m[1], 64)
-		w.gauge("i2pd_floodfills", "Number of known floodfills", v)
-	}
-	if m := reLeaseSets.FindStringSubmatch(html); m != nil {
-		v, _ := strconv.ParseFloat(m[1], 64)
-		w.gauge("i2pd_leasesets", "Number of known lease sets", v)
-	}
+// probe scrapes target once and returns a registry populated with the
+// blackbox-style probe outcome metrics plus the regular i2pd_* metrics for
+// that target, each stamped with a "target" label.
+func probe(ctx context.Context, s scraper, target string) *prometheus.Registry {
+	reg := prometheus.NewRegistry()
 
-	// Tunnels
-	if m := reClientTun.FindStringSubmatch(html); m != nil {
-		v, _ := strconv.ParseFloat(m[1], 64)
-		w.gauge("i2pd_client_tunnels", "Number of client tunnels", v)
-	}
-	if m := reTransitTun.FindStringSubmatch(html); m != nil {
-		v, _ := strconv.ParseFloat(m[1], 64)
-		w.gauge("i2pd_transit_tunnels", "Number of transit tunnels", v)
-	}
+	probeSuccess := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: namespace + "_probe_success",
+		Help: "Whether the probe of the target succeeded",
+	})
+	probeDuration := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: namespace + "_probe_duration_seconds",
+		Help: "Duration of the probe in seconds",
+	})
+	reg.MustRegister(probeSuccess, probeDuration)
 
-	// Info metrics
-	if m := reVersion.FindStringSubmatch(html); m != nil {
-		w.gaugeL("i2pd_version_info", "i2pd version", 1, "version", m[1])
-	}
-	if m := reCaps.FindStringSubmatch(html); m != nil {
-		w.gaugeL("i2pd_router_caps_info", "Router capability flags", 1, "caps", m[1])
-	}
+	start := time.Now()
+	stats, err := s.Scrape(ctx, target)
+	duration := time.Since(start)
+	probeDuration.Set(duration.Seconds())
 
-	// Services
-	for _, m := range reService.FindAllStringSubmatch(html, -1) {
-		name := sanitizeLabel(m[1])
-		w.gaugeL("i2pd_service_enabled", "Whether a service is enabled (1=yes, 0=no)",
-			bval(m[2] == "enabled"), "service", name)
+	if err != nil {
+		log.Printf("probe %s: scrape error: %v", target, err)
 	}
+	probeSuccess.Set(bval(err == nil))
 
-	return w.String()
+	reg.MustRegister(newI2PDCollector(stats, err == nil, duration, prometheus.Labels{"target": target}))
+	return reg
 }
 
-// promWriter emits Prometheus text exposition format.
-type promWriter struct {
-	b    strings.Builder
-	seen map[string]bool
+// i2pdCollector implements prometheus.Collector by formatting a single,
+// already-fetched scrape of i2pd's metrics. A fresh instance is built for
+// every probe so that repeated scrapes never accumulate stale series.
+type i2pdCollector struct {
+	stats          i2pdStats
+	up             bool
+	scrapeDuration time.Duration
+
+	scrapeDurationDesc *prometheus.Desc
+	upDesc             *prometheus.Desc
+	uptimeSeconds      *prometheus.Desc
+	networkStatus      *prometheus.Desc
+	tunnelSuccessRate  *prometheus.Desc
+	trafficTotal       *prometheus.Desc
+	trafficPerSecond   *prometheus.Desc
+	routers            *prometheus.Desc
+	floodfills         *prometheus.Desc
+	leaseSets          *prometheus.Desc
+	clientTunnels      *prometheus.Desc
+	transitTunnels     *prometheus.Desc
+	versionInfo        *prometheus.Desc
+	routerCapsInfo     *prometheus.Desc
+	serviceEnabled     *prometheus.Desc
+
+	transportPeers         *prometheus.Desc
+	transportBytes         *prometheus.Desc
+	transitTunnelBandwidth *prometheus.Desc
+	samSessions            *prometheus.Desc
+	clientTunnelInfo       *prometheus.Desc
 }
 
-func (w *promWriter) gauge(name, help string, value float64) {
-	w.emit(name, "gauge", help, value, "")
+func newI2PDCollector(stats i2pdStats, up bool, scrapeDuration time.Duration, constLabels prometheus.Labels) *i2pdCollector {
+	return &i2pdCollector{
+		stats:          stats,
+		up:             up,
+		scrapeDuration: scrapeDuration,
+
+		upDesc: prometheus.NewDesc(namespace+"_up",
+			"Whether the i2pd console is reachable", nil, constLabels),
+		scrapeDurationDesc: prometheus.NewDesc(namespace+"_scrape_duration_seconds",
+			"Time spent scraping i2pd console", nil, constLabels),
+		uptimeSeconds: prometheus.NewDesc(namespace+"_uptime_seconds",
+			"Router uptime in seconds", nil, constLabels),
+		networkStatus: prometheus.NewDesc(namespace+"_network_status",
+			"Network status (1=OK, 0=other)", []string{"protocol"}, constLabels),
+		tunnelSuccessRate: prometheus.NewDesc(namespace+"_tunnel_creation_success_rate_percent",
+			"Tunnel creation success rate", nil, constLabels),
+		trafficTotal: prometheus.NewDesc(namespace+"_traffic_bytes_total",
+			"Total traffic in bytes", []string{"direction"}, constLabels),
+		trafficPerSecond: prometheus.NewDesc(namespace+"_traffic_bytes_per_second",
+			"Traffic rate in bytes per second", []string{"direction"}, constLabels),
+		routers: prometheus.NewDesc(namespace+"_routers",
+			"Number of known routers", nil, constLabels),
+		floodfills: prometheus.NewDesc(namespace+"_floodfills",
+			"Number of known floodfills", nil, constLabels),
+		leaseSets: prometheus.NewDesc(namespace+"_leasesets",
+			"Number of known lease sets", nil, constLabels),
+		clientTunnels: prometheus.NewDesc(namespace+"_client_tunnels",
+			"Number of client tunnels", nil, constLabels),
+		transitTunnels: prometheus.NewDesc(namespace+"_transit_tunnels",
+			"Number of transit tunnels", nil, constLabels),
+		versionInfo: prometheus.NewDesc(namespace+"_version_info",
+			"i2pd version", []string{"version"}, constLabels),
+		routerCapsInfo: prometheus.NewDesc(namespace+"_router_caps_info",
+			"Router capability flags", []string{"caps"}, constLabels),
+		serviceEnabled: prometheus.NewDesc(namespace+"_service_enabled",
+			"Whether a service is enabled (1=yes, 0=no)", []string{"service"}, constLabels),
+
+		transportPeers: prometheus.NewDesc(namespace+"_transport_peers",
+			"Number of connected peers per transport and direction", []string{"transport", "direction"}, constLabels),
+		transportBytes: prometheus.NewDesc(namespace+"_transport_bytes_total",
+			"Total bytes transferred per transport and direction", []string{"transport", "direction"}, constLabels),
+		transitTunnelBandwidth: prometheus.NewDesc(namespace+"_transit_tunnel_bandwidth_bytes_total",
+			"Total bytes relayed by transit tunnels per bandwidth class", []string{"class"}, constLabels),
+		samSessions: prometheus.NewDesc(namespace+"_sam_sessions",
+			"Number of active SAM sessions", nil, constLabels),
+		clientTunnelInfo: prometheus.NewDesc(namespace+"_client_tunnel_info",
+			"Configured client/server tunnel (1=present)", []string{"name", "type"}, constLabels),
+	}
 }
 
-func (w *promWriter) gaugeL(name, help string, value float64, lk, lv string) {
-	w.emit(name, "gauge", help, value, fmt.Sprintf(`%s="%s"`, lk, lv))
+func (c *i2pdCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.upDesc
+	ch <- c.scrapeDurationDesc
+	ch <- c.uptimeSeconds
+	ch <- c.networkStatus
+	ch <- c.tunnelSuccessRate
+	ch <- c.trafficTotal
+	ch <- c.trafficPerSecond
+	ch <- c.routers
+	ch <- c.floodfills
+	ch <- c.leaseSets
+	ch <- c.clientTunnels
+	ch <- c.transitTunnels
+	ch <- c.versionInfo
+	ch <- c.routerCapsInfo
+	ch <- c.serviceEnabled
+	ch <- c.transportPeers
+	ch <- c.transportBytes
+	ch <- c.transitTunnelBandwidth
+	ch <- c.samSessions
+	ch <- c.clientTunnelInfo
 }
 
-func (w *promWriter) counterL(name, help string, value float64, lk, lv string) {
-	w.emit(name, "counter", help, value, fmt.Sprintf(`%s="%s"`, lk, lv))
+func (c *i2pdCollector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(c.scrapeDurationDesc, prometheus.GaugeValue, c.scrapeDuration.Seconds())
+
+	if !c.up {
+		ch <- prometheus.MustNewConstMetric(c.upDesc, prometheus.GaugeValue, 0)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.upDesc, prometheus.GaugeValue, 1)
+	c.collectStats(ch)
 }
 
-func (w *promWriter) emit(name, typ, help string, value float64, labelExpr string) {
-	if !w.seen[name] {
-		if help != "" {
-			fmt.Fprintf(&w.b, "# HELP %s %s\n", name, help)
-		}
-		fmt.Fprintf(&w.b, "# TYPE %s %s\n", name, typ)
-		w.seen[name] = true
+// collectStats emits every field present in c.stats as a const metric on ch.
+func (c *i2pdCollector) collectStats(ch chan<- prometheus.Metric) {
+	s := c.stats
+
+	if s.Uptime != nil {
+		ch <- prometheus.MustNewConstMetric(c.uptimeSeconds, prometheus.GaugeValue, s.Uptime.Seconds())
 	}
-	if labelExpr != "" {
-		fmt.Fprintf(&w.b, "%s{%s} %s\n", name, labelExpr, fmtFloat(value))
-	} else {
-		fmt.Fprintf(&w.b, "%s %s\n", name, fmtFloat(value))
+	if s.NetStatusV4 != nil {
+		ch <- prometheus.MustNewConstMetric(c.networkStatus, prometheus.GaugeValue, bval(*s.NetStatusV4), "v4")
+	}
+	if s.NetStatusV6 != nil {
+		ch <- prometheus.MustNewConstMetric(c.networkStatus, prometheus.GaugeValue, bval(*s.NetStatusV6), "v6")
+	}
+	if s.TunnelSuccessRate != nil {
+		ch <- prometheus.MustNewConstMetric(c.tunnelSuccessRate, prometheus.GaugeValue, *s.TunnelSuccessRate)
 	}
-}
 
-func (w *promWriter) String() string {
-	return w.b.String()
-}
+	for _, t := range s.Traffic {
+		ch <- prometheus.MustNewConstMetric(c.trafficTotal, prometheus.CounterValue, t.TotalBytes, t.Direction)
+		ch <- prometheus.MustNewConstMetric(c.trafficPerSecond, prometheus.GaugeValue, t.RateBytesPerSecond, t.Direction)
+	}
 
-func fmtFloat(v float64) string {
-	if v == math.Trunc(v) && !math.IsInf(v, 0) && !math.IsNaN(v) && math.Abs(v) < 1e15 {
-		return strconv.FormatInt(int64(v), 10)
+	if s.Routers != nil {
+		ch <- prometheus.MustNewConstMetric(c.routers, prometheus.GaugeValue, *s.Routers)
+	}
+	if s.Floodfills != nil {
+		ch <- prometheus.MustNewConstMetric(c.floodfills, prometheus.GaugeValue, *s.Floodfills)
+	}
+	if s.LeaseSets != nil {
+		ch <- prometheus.MustNewConstMetric(c.leaseSets, prometheus.GaugeValue, *s.LeaseSets)
+	}
+	if s.ClientTunnels != nil {
+		ch <- prometheus.MustNewConstMetric(c.clientTunnels, prometheus.GaugeValue, *s.ClientTunnels)
+	}
+	if s.TransitTunnels != nil {
+		ch <- prometheus.MustNewConstMetric(c.transitTunnels, prometheus.GaugeValue, *s.TransitTunnels)
 	}
-	return strconv.FormatFloat(v, 'f', -1, 64)
-}
 
-func unitBytes(unit string) float64 {
-	switch strings.TrimSpace(unit) {
-	case "KiB":
-		return 1024
-	case "MiB":
-		return 1 << 20
-	case "GiB":
-		return 1 << 30
-	case "TiB":
-		return 1 << 40
-	default:
-		return 1
+	if s.Version != "" {
+		ch <- prometheus.MustNewConstMetric(c.versionInfo, prometheus.GaugeValue, 1, s.Version)
+	}
+	if s.Caps != "" {
+		ch <- prometheus.MustNewConstMetric(c.routerCapsInfo, prometheus.GaugeValue, 1, s.Caps)
+	}
+	for name, enabled := range s.Services {
+		ch <- prometheus.MustNewConstMetric(c.serviceEnabled, prometheus.GaugeValue, bval(enabled), name)
+	}
+
+	for _, p := range s.TransportPeers {
+		ch <- prometheus.MustNewConstMetric(c.transportPeers, prometheus.GaugeValue, p.Peers, p.Transport, p.Direction)
+	}
+	for _, b := range s.TransportBytes {
+		ch <- prometheus.MustNewConstMetric(c.transportBytes, prometheus.CounterValue, b.Bytes, b.Transport, b.Direction)
+	}
+	for _, b := range s.TransitTunnelBandwidth {
+		ch <- prometheus.MustNewConstMetric(c.transitTunnelBandwidth, prometheus.CounterValue, b.Bytes, b.Class)
+	}
+	if s.SAMSessions != nil {
+		ch <- prometheus.MustNewConstMetric(c.samSessions, prometheus.GaugeValue, *s.SAMSessions)
+	}
+	for _, t := range s.ClientTunnelInfo {
+		ch <- prometheus.MustNewConstMetric(c.clientTunnelInfo, prometheus.GaugeValue, 1, t.Name, t.Type)
 	}
 }
 
@@ -251,15 +365,3 @@ func bval(b bool) float64 {
 	}
 	return 0
 }
-
-func sanitizeLabel(s string) string {
-	s = strings.ToLower(strings.TrimSpace(s))
-	s = strings.ReplaceAll(s, " ", "_")
-	var b strings.Builder
-	for _, c := range s {
-		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '_' {
-			b.WriteRune(c)
-		}
-	}
-	return b.String()
-}
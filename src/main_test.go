@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const testHTML = `<!DOCTYPE html>
+<html lang="en">
+<head><title>Purple I2P Webconsole</title></head>
+<body>
+<div class="content">
+
+<b>Uptime:</b> 1 day, 1 hours, 1 minutes, 1 seconds<br>
+<b>Network status:</b> OK<br>
+<b>Network status v6:</b> OK<br>
+<b>Tunnel creation success rate:</b> 4%<br>
+<b>Received:</b> 100.1 GiB (3301.31 KiB/s)<br>
+<b>Sent:</b> 100.2 GiB (3300.43 KiB/s)<br>
+<b>Transit:</b> 98 GiB (3000.55 KiB/s)<br>
+<b>Data path:</b> /home/i2pd/data<br>
+<b>Router Ident:</b>redacted<br>
+<b>Router Caps:</b> PR<br>
+<b>Version:</b>2.59.0<br>
+<b>Routers:</b> 10100&nbsp;&nbsp;&nbsp;<b>Floodfills:</b> 3612&nbsp;&nbsp;&nbsp;<b>LeaseSets:</b> 0<br>
+<b>Client Tunnels:</b> 10&nbsp;&nbsp;&nbsp;<b>Transit Tunnels:</b> 1234<br>
+
+<table class="services">
+<caption>Services</caption>
+<tbody>
+<tr><td>HTTP Proxy</td><td class='enabled'>Enabled</td></tr>
+<tr><td>SOCKS Proxy</td><td class='enabled'>Enabled</td></tr>
+<tr><td>BOB</td><td class='disabled'>Disabled</td></tr>
+<tr><td>SAM</td><td class='enabled'>Enabled</td></tr>
+<tr><td>I2CP</td><td class='disabled'>Disabled</td></tr>
+<tr><td>I2PControl</td><td class='disabled'>Disabled</td></tr>
+</tbody>
+</table>
+
+</div>
+</body>
+</html>`
+
+// gatherMetrics registers collector on a fresh registry, gathers once, and
+// flattens the result into "name{label=value,...}" -> value for easy lookup.
+func gatherMetrics(t *testing.T, c prometheus.Collector) map[string]float64 {
+	t.Helper()
+
+	reg := prometheus.NewPedanticRegistry()
+	if err := reg.Register(c); err != nil {
+		t.Fatalf("register collector: %v", err)
+	}
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+
+	got := make(map[string]float64)
+	for _, mf := range mfs {
+		for _, m := range mf.GetMetric() {
+			key := mf.GetName()
+			var labels []string
+			for _, l := range m.GetLabel() {
+				labels = append(labels, l.GetName()+"=\""+l.GetValue()+"\"")
+			}
+			if len(labels) > 0 {
+				key += "{" + strings.Join(labels, ",") + "}"
+			}
+			switch {
+			case m.Gauge != nil:
+				got[key] = m.Gauge.GetValue()
+			case m.Counter != nil:
+				got[key] = m.Counter.GetValue()
+			}
+		}
+	}
+	return got
+}
+
+func newTestServer(t *testing.T, html string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(html))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func probeTarget(t *testing.T, target string) map[string]float64 {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	return gatherMetrics(t, probe(ctx, newWebconsoleScraper(nil), target))
+}
+
+func TestProbeGather(t *testing.T) {
+	srv := newTestServer(t, testHTML)
+	got := probeTarget(t, srv.URL)
+	tl := `target="` + srv.URL + `"`
+
+	expected := map[string]float64{
+		"i2pd_probe_success":                                        1,
+		`i2pd_up{` + tl + `}`:                                       1,
+		`i2pd_uptime_seconds{` + tl + `}`:                           90061, // 1*86400 + 1*3600 + 1*60 + 1
+		`i2pd_routers{` + tl + `}`:                                  10100,
+		`i2pd_floodfills{` + tl + `}`:                               3612,
+		`i2pd_leasesets{` + tl + `}`:                                0,
+		`i2pd_client_tunnels{` + tl + `}`:                           10,
+		`i2pd_transit_tunnels{` + tl + `}`:                          1234,
+		`i2pd_tunnel_creation_success_rate_percent{` + tl + `}`:     4,
+		`i2pd_network_status{protocol="v4",` + tl + `}`:             1,
+		`i2pd_network_status{protocol="v6",` + tl + `}`:             1,
+		`i2pd_version_info{` + tl + `,version="2.59.0"}`:            1,
+		`i2pd_router_caps_info{caps="PR",` + tl + `}`:               1,
+		`i2pd_service_enabled{service="http_proxy",` + tl + `}`:     1,
+		`i2pd_service_enabled{service="socks_proxy",` + tl + `}`:    1,
+		`i2pd_service_enabled{service="bob",` + tl + `}`:            0,
+		`i2pd_service_enabled{service="sam",` + tl + `}`:            1,
+		`i2pd_service_enabled{service="i2cp",` + tl + `}`:           0,
+		`i2pd_service_enabled{service="i2pcontrol",` + tl + `}`:     0,
+		`i2pd_traffic_bytes_total{direction="received",` + tl + `}`: 100.1 * (1 << 30),
+		`i2pd_traffic_bytes_total{direction="sent",` + tl + `}`:     100.2 * (1 << 30),
+		`i2pd_traffic_bytes_total{direction="transit",` + tl + `}`:  98 * (1 << 30),
+	}
+
+	for metric, want := range expected {
+		gotVal, ok := got[metric]
+		if !ok {
+			t.Errorf("expected metric %q not found", metric)
+			continue
+		}
+		if gotVal != want {
+			t.Errorf("metric %q = %v, want %v", metric, gotVal, want)
+		}
+	}
+}
+
+func TestProbeUptimeParsing(t *testing.T) {
+	cases := []struct {
+		html string
+		want float64
+	}{
+		{`<b>Uptime:</b> 0 days, 0 hours, 5 minutes, 30 seconds<br>`, 330},
+		{`<b>Uptime:</b> 2 days, 12 hours, 0 minutes, 0 seconds<br>`, 216000},
+		{`<b>Uptime:</b> 0 days, 0 hours, 0 minutes, 1 seconds<br>`, 1},
+	}
+	for _, tc := range cases {
+		srv := newTestServer(t, tc.html)
+		got := probeTarget(t, srv.URL)
+		key := `i2pd_uptime_seconds{target="` + srv.URL + `"}`
+		if got[key] != tc.want {
+			t.Errorf("for %q: %s = %v, want %v", tc.html, key, got[key], tc.want)
+		}
+	}
+}
+
+func TestProbeTrafficParsing(t *testing.T) {
+	srv := newTestServer(t, `<b>Received:</b> 1 GiB (1024 KiB/s)<br>`)
+	got := probeTarget(t, srv.URL)
+
+	totalKey := `i2pd_traffic_bytes_total{direction="received",target="` + srv.URL + `"}`
+	rateKey := `i2pd_traffic_bytes_per_second{direction="received",target="` + srv.URL + `"}`
+
+	if v := got[totalKey]; v != 1073741824 {
+		t.Errorf("%s = %v, want 1073741824", totalKey, v)
+	}
+	if v := got[rateKey]; v != 1048576 {
+		t.Errorf("%s = %v, want 1048576", rateKey, v)
+	}
+}
+
+func TestProbeDown(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	got := probeTarget(t, srv.URL)
+
+	if got["i2pd_probe_success"] != 0 {
+		t.Errorf("i2pd_probe_success = %v, want 0", got["i2pd_probe_success"])
+	}
+	if got[`i2pd_up{target="`+srv.URL+`"}`] != 0 {
+		t.Errorf("i2pd_up = %v, want 0", got[`i2pd_up{target="`+srv.URL+`"}`])
+	}
+	if _, ok := got[`i2pd_routers{target="`+srv.URL+`"}`]; ok {
+		t.Errorf("i2pd_routers should not be emitted when the scrape fails")
+	}
+}
+
+func TestSanitizeLabel(t *testing.T) {
+	cases := map[string]string{
+		"HTTP Proxy":  "http_proxy",
+		"SOCKS Proxy": "socks_proxy",
+		"BOB":         "bob",
+		"SAM":         "sam",
+		"I2CP":        "i2cp",
+		"I2PControl":  "i2pcontrol",
+	}
+	for input, want := range cases {
+		got := sanitizeLabel(input)
+		if got != want {
+			t.Errorf("sanitizeLabel(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
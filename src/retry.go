@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Backoff parameters modeled on gRPC's default reconnect backoff: start at
+// baseDelay, grow by factor per attempt up to maxDelay, then jitter the
+// result by +/-jitter to avoid every exporter instance retrying in lockstep.
+const (
+	retryBaseDelay = 200 * time.Millisecond
+	retryFactor    = 1.6
+	retryJitter    = 0.2
+	retryMaxDelay  = 5 * time.Second
+)
+
+// scrapeAttemptsTotal and scrapeRetriesTotal are deliberately NOT labeled by
+// target: target comes straight from the caller-supplied ?target= query
+// parameter, and a CounterVec keyed on it would accumulate one series per
+// distinct (or rotating, or scanned) target forever on the exporter's own
+// /metrics. "outcome" is bounded to "success"/"error", so it's safe to keep.
+var (
+	scrapeAttemptsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: namespace + "_scrape_attempts_total",
+		Help: "Total number of scrape attempts, by outcome",
+	}, []string{"outcome"})
+	scrapeRetriesTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: namespace + "_scrape_retries_total",
+		Help: "Total number of scrape retries after a transient failure",
+	})
+)
+
+// retryingScraper wraps another scraper and retries transient failures with
+// exponential backoff and jitter, so a momentary connection error during
+// i2pd tunnel rebuild or router restart doesn't flip i2pd_up to 0 on its
+// own. It gives up once maxAttempts is reached or the next backoff would
+// run past ctx's deadline.
+type retryingScraper struct {
+	inner       scraper
+	maxAttempts int
+}
+
+func newRetryingScraper(inner scraper, maxAttempts int) *retryingScraper {
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &retryingScraper{inner: inner, maxAttempts: maxAttempts}
+}
+
+func (r *retryingScraper) Scrape(ctx context.Context, target string) (i2pdStats, error) {
+	var lastErr error
+
+	for attempt := 0; attempt < r.maxAttempts; attempt++ {
+		stats, err := r.inner.Scrape(ctx, target)
+		if err == nil {
+			scrapeAttemptsTotal.WithLabelValues("success").Inc()
+			return stats, nil
+		}
+
+		lastErr = err
+		scrapeAttemptsTotal.WithLabelValues("error").Inc()
+
+		if attempt == r.maxAttempts-1 {
+			break
+		}
+
+		delay := retryBackoff(attempt)
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+			break
+		}
+
+		scrapeRetriesTotal.Inc()
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return i2pdStats{}, ctx.Err()
+		}
+	}
+
+	return i2pdStats{}, lastErr
+}
+
+// retryBackoff returns the delay before retry attempt n+1 (0-indexed),
+// following delay = min(maxDelay, baseDelay*factor^n) jittered by +/-jitter.
+func retryBackoff(attempt int) time.Duration {
+	delay := float64(retryBaseDelay) * math.Pow(retryFactor, float64(attempt))
+	if delay > float64(retryMaxDelay) {
+		delay = float64(retryMaxDelay)
+	}
+	delay *= 1 + retryJitter*(2*rand.Float64()-1)
+	if delay < 0 {
+		delay = 0
+	}
+	return time.Duration(delay)
+}
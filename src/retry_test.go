@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type stubScraper struct {
+	failures int
+	calls    int
+}
+
+func (s *stubScraper) Scrape(ctx context.Context, target string) (i2pdStats, error) {
+	s.calls++
+	if s.calls <= s.failures {
+		return i2pdStats{}, errors.New("transient error")
+	}
+	return i2pdStats{}, nil
+}
+
+func TestRetryingScraperSucceedsAfterTransientFailures(t *testing.T) {
+	inner := &stubScraper{failures: 2}
+	r := newRetryingScraper(inner, 5)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if _, err := r.Scrape(ctx, "target"); err != nil {
+		t.Fatalf("Scrape: %v", err)
+	}
+	if inner.calls != 3 {
+		t.Errorf("calls = %d, want 3 (2 failures + 1 success)", inner.calls)
+	}
+}
+
+func TestRetryingScraperGivesUpAfterMaxAttempts(t *testing.T) {
+	inner := &stubScraper{failures: 100}
+	r := newRetryingScraper(inner, 3)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if _, err := r.Scrape(ctx, "target"); err == nil {
+		t.Fatal("expected an error once attempts are exhausted")
+	}
+	if inner.calls != 3 {
+		t.Errorf("calls = %d, want 3", inner.calls)
+	}
+}
+
+func TestRetryingScraperAbortsBeforeDeadline(t *testing.T) {
+	inner := &stubScraper{failures: 100}
+	r := newRetryingScraper(inner, 100)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := r.Scrape(ctx, "target"); err == nil {
+		t.Fatal("expected an error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Scrape took %v, expected it to abort well before exhausting 100 attempts", elapsed)
+	}
+	if inner.calls >= 100 {
+		t.Errorf("calls = %d, expected early abort before reaching max attempts", inner.calls)
+	}
+}
+
+func TestRetryBackoffRespectsMaxDelay(t *testing.T) {
+	for attempt := 0; attempt < 20; attempt++ {
+		if d := retryBackoff(attempt); d > retryMaxDelay+retryMaxDelay/5 {
+			t.Errorf("retryBackoff(%d) = %v, want <= %v (with jitter)", attempt, d, retryMaxDelay)
+		}
+	}
+}
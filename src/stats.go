@@ -0,0 +1,68 @@
+package main
+
+import "time"
+
+// i2pdStats holds the router metrics the exporter knows how to report,
+// populated by whichever scrape backend is active. Fields are pointers (or
+// left at their zero value for strings/maps) so the emission layer in
+// main.go can tell "not reported by this backend" apart from "reported as
+// zero/empty".
+type i2pdStats struct {
+	Uptime            *time.Duration
+	NetStatusV4       *bool
+	NetStatusV6       *bool
+	TunnelSuccessRate *float64
+	Traffic           []trafficSample
+	Routers           *float64
+	Floodfills        *float64
+	LeaseSets         *float64
+	ClientTunnels     *float64
+	TransitTunnels    *float64
+	Version           string
+	Caps              string
+	Services          map[string]bool
+
+	TransportPeers         []transportPeerSample
+	TransportBytes         []transportByteSample
+	TransitTunnelBandwidth []transitBandwidthSample
+	SAMSessions            *float64
+	ClientTunnelInfo       []clientTunnelSample
+}
+
+// trafficSample is one direction (received/sent/transit) of traffic
+// counters as reported by a scrape backend.
+type trafficSample struct {
+	Direction          string
+	TotalBytes         float64
+	RateBytesPerSecond float64
+}
+
+// transportPeerSample is the peer count for one direction of one transport
+// (NTCP2/SSU2), from the webconsole's transports page.
+type transportPeerSample struct {
+	Transport string
+	Direction string
+	Peers     float64
+}
+
+// transportByteSample is the cumulative byte count for one direction of one
+// transport, from the webconsole's transports page.
+type transportByteSample struct {
+	Transport string
+	Direction string
+	Bytes     float64
+}
+
+// transitBandwidthSample is the total bytes relayed by transit tunnels of
+// one bandwidth class (L/O/P/X), from the webconsole's transit_tunnels page.
+type transitBandwidthSample struct {
+	Class string
+	Bytes float64
+}
+
+// clientTunnelSample names one configured client/server tunnel, from the
+// webconsole's i2p_tunnels page.
+type clientTunnelSample struct {
+	Name string
+	Type string
+}
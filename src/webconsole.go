@@ -0,0 +1,336 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// webconsolePage identifies one page of the i2pd webconsole. pageHome is
+// always fetched; the rest are optional and selected with -pages.
+type webconsolePage string
+
+const (
+	pageHome           webconsolePage = "home"
+	pageTransports     webconsolePage = "transports"
+	pageTransitTunnels webconsolePage = "transit_tunnels"
+	pageSAMSessions    webconsolePage = "sam_sessions"
+	pageI2PTunnels     webconsolePage = "i2p_tunnels"
+)
+
+var allWebconsolePages = []webconsolePage{pageHome, pageTransports, pageTransitTunnels, pageSAMSessions, pageI2PTunnels}
+
+func validWebconsolePage(p webconsolePage) bool {
+	for _, known := range allWebconsolePages {
+		if p == known {
+			return true
+		}
+	}
+	return false
+}
+
+// maxConcurrentPageFetches bounds how many auxiliary webconsole pages are
+// fetched in parallel per scrape, so a -pages list with every page doesn't
+// open more connections to the router than necessary.
+const maxConcurrentPageFetches = 4
+
+var (
+	reUptime      = regexp.MustCompile(`(?i)<b>Uptime:</b>\s*(.+?)<br`)
+	reUptimePart  = regexp.MustCompile(`(\d+)\s+(day|hour|minute|second)`)
+	reNetStatus   = regexp.MustCompile(`(?i)<b>Network status:</b>\s*(\w+)`)
+	reNetStatusV6 = regexp.MustCompile(`(?i)<b>Network status v6:</b>\s*(\w+)`)
+	reTunnelRate  = regexp.MustCompile(`(?i)<b>Tunnel creation success rate:</b>\s*([\d.]+)\s*%`)
+	reTraffic     = regexp.MustCompile(`(?i)<b>(Received|Sent|Transit):</b>\s*([\d.]+)\s*(\w+)\s*\(([\d.]+)\s*(\w+/s)\)`)
+	reRouters     = regexp.MustCompile(`(?i)<b>Routers:</b>\s*(\d+)`)
+	reFloodfills  = regexp.MustCompile(`(?i)<b>Floodfills:</b>\s*(\d+)`)
+	reLeaseSets   = regexp.MustCompile(`(?i)<b>LeaseSets:</b>\s*(\d+)`)
+	reClientTun   = regexp.MustCompile(`(?i)<b>Client Tunnels:</b>\s*(\d+)`)
+	reTransitTun  = regexp.MustCompile(`(?i)<b>Transit Tunnels:</b>\s*(\d+)`)
+	reVersion     = regexp.MustCompile(`(?i)<b>Version:</b>\s*([\d.]+)`)
+	reCaps        = regexp.MustCompile(`(?i)<b>Router Caps:</b>\s*(\w+)`)
+	reService     = regexp.MustCompile(`<tr><td>([^<]+)</td><td\s+class='(enabled|disabled)'`)
+
+	reTransportSection = regexp.MustCompile(`(?is)<b>(NTCP2|SSU2)</b>.*?<b>Peers:</b>\s*\d+\s*<small>\(in:\s*(\d+),\s*out:\s*(\d+)\)</small>.*?<b>Send:</b>\s*([\d.]+)\s*Bytes.*?<b>Receive:</b>\s*([\d.]+)\s*Bytes`)
+	reTransitBandwidth = regexp.MustCompile(`(?i)<td class="([LOPX])">\s*(\d+)\s*</td>`)
+	reSAMSessions      = regexp.MustCompile(`(?i)<b>SAM Sessions:</b>\s*(\d+)`)
+	reClientTunnel     = regexp.MustCompile(`(?i)<tr><td>([^<]+)</td><td>(client|server)</td></tr>`)
+)
+
+// webconsoleScraper is the original scrape backend: it fetches the i2pd web
+// console and regex-scrapes the HTML for metrics. It is fragile to upstream
+// markup and localization changes, but needs no configuration on the router
+// side, so it remains the default backend.
+type webconsoleScraper struct {
+	client *http.Client
+	pages  []webconsolePage
+}
+
+func newWebconsoleScraper(pages []webconsolePage) *webconsoleScraper {
+	return &webconsoleScraper{client: &http.Client{}, pages: pages}
+}
+
+func (s *webconsoleScraper) Scrape(ctx context.Context, target string) (i2pdStats, error) {
+	html, err := fetchWebconsolePage(ctx, s.client, target, pageHome)
+	if err != nil {
+		return i2pdStats{}, err
+	}
+	stats := parseWebconsoleStats(html)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentPageFetches)
+
+	for _, page := range s.pages {
+		if page == pageHome {
+			continue
+		}
+
+		page := page
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			body, err := fetchWebconsolePage(ctx, s.client, target, page)
+			if err != nil {
+				log.Printf("fetch %s page: %v", page, err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch page {
+			case pageTransports:
+				parseTransportsInto(&stats, body)
+			case pageTransitTunnels:
+				parseTransitTunnelsInto(&stats, body)
+			case pageSAMSessions:
+				parseSAMSessionsInto(&stats, body)
+			case pageI2PTunnels:
+				parseClientTunnelsInto(&stats, body)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return stats, nil
+}
+
+// fetchWebconsolePage fetches one page of the i2pd webconsole, honoring
+// ctx's deadline.
+func fetchWebconsolePage(ctx context.Context, client *http.Client, target string, page webconsolePage) (string, error) {
+	url := target
+	if page != pageHome {
+		url += "?page=" + string(page)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("i2pd returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// parseWebconsoleStats extracts every metric it recognizes from the i2pd web
+// console HTML. Fields whose regex doesn't match are simply left unset.
+func parseWebconsoleStats(html string) i2pdStats {
+	var stats i2pdStats
+
+	if m := reUptime.FindStringSubmatch(html); m != nil {
+		var secs float64
+		for _, p := range reUptimePart.FindAllStringSubmatch(m[1], -1) {
+			n, _ := strconv.ParseFloat(p[1], 64)
+			switch {
+			case strings.HasPrefix(p[2], "day"):
+				secs += n * 86400
+			case strings.HasPrefix(p[2], "hour"):
+				secs += n * 3600
+			case strings.HasPrefix(p[2], "minute"):
+				secs += n * 60
+			case strings.HasPrefix(p[2], "second"):
+				secs += n
+			}
+		}
+		uptime := time.Duration(secs * float64(time.Second))
+		stats.Uptime = &uptime
+	}
+
+	if m := reNetStatus.FindStringSubmatch(html); m != nil {
+		ok := m[1] == "OK"
+		stats.NetStatusV4 = &ok
+	}
+	if m := reNetStatusV6.FindStringSubmatch(html); m != nil {
+		ok := m[1] == "OK"
+		stats.NetStatusV6 = &ok
+	}
+
+	if m := reTunnelRate.FindStringSubmatch(html); m != nil {
+		v, _ := strconv.ParseFloat(m[1], 64)
+		stats.TunnelSuccessRate = &v
+	}
+
+	for _, m := range reTraffic.FindAllStringSubmatch(html, -1) {
+		total, _ := strconv.ParseFloat(m[2], 64)
+		total *= unitBytes(m[3])
+		rate, _ := strconv.ParseFloat(m[4], 64)
+		rate *= unitBytes(strings.TrimSuffix(m[5], "/s"))
+
+		stats.Traffic = append(stats.Traffic, trafficSample{
+			Direction:          strings.ToLower(m[1]),
+			TotalBytes:         total,
+			RateBytesPerSecond: rate,
+		})
+	}
+
+	if m := reRouters.FindStringSubmatch(html); m != nil {
+		v, _ := strconv.ParseFloat(m[1], 64)
+		stats.Routers = &v
+	}
+	if m := reFloodfills.FindStringSubmatch(html); m != nil {
+		v, _ := strconv.ParseFloat(m[1], 64)
+		stats.Floodfills = &v
+	}
+	if m := reLeaseSets.FindStringSubmatch(html); m != nil {
+		v, _ := strconv.ParseFloat(m[1], 64)
+		stats.LeaseSets = &v
+	}
+
+	if m := reClientTun.FindStringSubmatch(html); m != nil {
+		v, _ := strconv.ParseFloat(m[1], 64)
+		stats.ClientTunnels = &v
+	}
+	if m := reTransitTun.FindStringSubmatch(html); m != nil {
+		v, _ := strconv.ParseFloat(m[1], 64)
+		stats.TransitTunnels = &v
+	}
+
+	if m := reVersion.FindStringSubmatch(html); m != nil {
+		stats.Version = m[1]
+	}
+	if m := reCaps.FindStringSubmatch(html); m != nil {
+		stats.Caps = m[1]
+	}
+
+	for _, m := range reService.FindAllStringSubmatch(html, -1) {
+		if stats.Services == nil {
+			stats.Services = make(map[string]bool)
+		}
+		stats.Services[sanitizeLabel(m[1])] = m[2] == "enabled"
+	}
+
+	return stats
+}
+
+// parseTransportsInto extracts per-transport peer counts and byte counters
+// from the webconsole's transports page and merges them into stats.
+func parseTransportsInto(stats *i2pdStats, html string) {
+	for _, m := range reTransportSection.FindAllStringSubmatch(html, -1) {
+		transport := strings.ToLower(m[1])
+
+		in, _ := strconv.ParseFloat(m[2], 64)
+		out, _ := strconv.ParseFloat(m[3], 64)
+		stats.TransportPeers = append(stats.TransportPeers,
+			transportPeerSample{Transport: transport, Direction: "in", Peers: in},
+			transportPeerSample{Transport: transport, Direction: "out", Peers: out},
+		)
+
+		sent, _ := strconv.ParseFloat(m[4], 64)
+		received, _ := strconv.ParseFloat(m[5], 64)
+		stats.TransportBytes = append(stats.TransportBytes,
+			transportByteSample{Transport: transport, Direction: "sent", Bytes: sent},
+			transportByteSample{Transport: transport, Direction: "received", Bytes: received},
+		)
+	}
+}
+
+// parseTransitTunnelsInto extracts the per-bandwidth-class transit byte
+// counters from the webconsole's transit_tunnels page and merges them into
+// stats.
+func parseTransitTunnelsInto(stats *i2pdStats, html string) {
+	totals := make(map[string]float64)
+	var classes []string
+	for _, m := range reTransitBandwidth.FindAllStringSubmatch(html, -1) {
+		bytes, _ := strconv.ParseFloat(m[2], 64)
+		if _, seen := totals[m[1]]; !seen {
+			classes = append(classes, m[1])
+		}
+		totals[m[1]] += bytes
+	}
+	for _, class := range classes {
+		stats.TransitTunnelBandwidth = append(stats.TransitTunnelBandwidth, transitBandwidthSample{
+			Class: class,
+			Bytes: totals[class],
+		})
+	}
+}
+
+// parseSAMSessionsInto extracts the SAM session count from the webconsole's
+// sam_sessions page and merges it into stats.
+func parseSAMSessionsInto(stats *i2pdStats, html string) {
+	if m := reSAMSessions.FindStringSubmatch(html); m != nil {
+		v, _ := strconv.ParseFloat(m[1], 64)
+		stats.SAMSessions = &v
+	}
+}
+
+// parseClientTunnelsInto extracts the configured client/server tunnels from
+// the webconsole's i2p_tunnels page and merges them into stats.
+func parseClientTunnelsInto(stats *i2pdStats, html string) {
+	for _, m := range reClientTunnel.FindAllStringSubmatch(html, -1) {
+		stats.ClientTunnelInfo = append(stats.ClientTunnelInfo, clientTunnelSample{
+			Name: sanitizeLabel(m[1]),
+			Type: m[2],
+		})
+	}
+}
+
+func unitBytes(unit string) float64 {
+	switch strings.TrimSpace(unit) {
+	case "KiB":
+		return 1024
+	case "MiB":
+		return 1 << 20
+	case "GiB":
+		return 1 << 30
+	case "TiB":
+		return 1 << 40
+	default:
+		return 1
+	}
+}
+
+func sanitizeLabel(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.ReplaceAll(s, " ", "_")
+	var b strings.Builder
+	for _, c := range s {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '_' {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
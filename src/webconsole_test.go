@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const transportsHTML = `
+<b>NTCP2</b>
+<b>Peers:</b> 15 <small>(in: 10, out: 5)</small>
+<b>Send:</b> 200 Bytes
+<b>Receive:</b> 100 Bytes
+<b>SSU2</b>
+<b>Peers:</b> 8 <small>(in: 3, out: 5)</small>
+<b>Send:</b> 50 Bytes
+<b>Receive:</b> 25 Bytes
+`
+
+const transitTunnelsHTML = `
+<table>
+<tr><td class="L">100</td></tr>
+<tr><td class="O">200</td></tr>
+</table>
+`
+
+const samSessionsHTML = `<b>SAM Sessions:</b> 2<br>`
+
+const i2pTunnelsHTML = `
+<table>
+<tr><td>irc</td><td>client</td></tr>
+<tr><td>http</td><td>server</td></tr>
+</table>
+`
+
+func newPagedTestServer(t *testing.T, pages map[string]string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		if page == "" {
+			page = "home"
+		}
+		w.Write([]byte(pages[page]))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestProbeAuxiliaryPages(t *testing.T) {
+	srv := newPagedTestServer(t, map[string]string{
+		"home":            testHTML,
+		"transports":      transportsHTML,
+		"transit_tunnels": transitTunnelsHTML,
+		"sam_sessions":    samSessionsHTML,
+		"i2p_tunnels":     i2pTunnelsHTML,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	scraper := newWebconsoleScraper(allWebconsolePages)
+	got := gatherMetrics(t, probe(ctx, scraper, srv.URL))
+	tl := `target="` + srv.URL + `"`
+
+	expected := map[string]float64{
+		`i2pd_transport_peers{direction="in",` + tl + `,transport="ntcp2"}`:             10,
+		`i2pd_transport_peers{direction="out",` + tl + `,transport="ntcp2"}`:            5,
+		`i2pd_transport_peers{direction="in",` + tl + `,transport="ssu2"}`:              3,
+		`i2pd_transport_peers{direction="out",` + tl + `,transport="ssu2"}`:             5,
+		`i2pd_transport_bytes_total{direction="sent",` + tl + `,transport="ntcp2"}`:     200,
+		`i2pd_transport_bytes_total{direction="received",` + tl + `,transport="ntcp2"}`: 100,
+		`i2pd_transit_tunnel_bandwidth_bytes_total{class="L",` + tl + `}`:               100,
+		`i2pd_transit_tunnel_bandwidth_bytes_total{class="O",` + tl + `}`:               200,
+		`i2pd_sam_sessions{` + tl + `}`:                                                 2,
+		`i2pd_client_tunnel_info{name="irc",` + tl + `,type="client"}`:                  1,
+		`i2pd_client_tunnel_info{name="http",` + tl + `,type="server"}`:                 1,
+	}
+
+	for metric, want := range expected {
+		gotVal, ok := got[metric]
+		if !ok {
+			t.Errorf("expected metric %q not found", metric)
+			continue
+		}
+		if gotVal != want {
+			t.Errorf("metric %q = %v, want %v", metric, gotVal, want)
+		}
+	}
+}
+
+func TestParsePages(t *testing.T) {
+	cases := []struct {
+		flagValue string
+		want      []webconsolePage
+		wantErr   bool
+	}{
+		{"", []webconsolePage{pageHome}, false},
+		{"transports", []webconsolePage{pageHome, pageTransports}, false},
+		{"transports,sam_sessions", []webconsolePage{pageHome, pageTransports, pageSAMSessions}, false},
+		{"home,transports", []webconsolePage{pageHome, pageTransports}, false},
+		{"bogus", nil, true},
+	}
+
+	for _, tc := range cases {
+		got, err := parsePages(tc.flagValue)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("parsePages(%q): expected an error", tc.flagValue)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parsePages(%q): unexpected error: %v", tc.flagValue, err)
+			continue
+		}
+		if len(got) != len(tc.want) {
+			t.Errorf("parsePages(%q) = %v, want %v", tc.flagValue, got, tc.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("parsePages(%q) = %v, want %v", tc.flagValue, got, tc.want)
+				break
+			}
+		}
+	}
+}